@@ -0,0 +1,116 @@
+package gorbac
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCycleDetected is returned by SetParent/SetParents when binding the
+// given parent would make the role hierarchy cyclic.
+var ErrCycleDetected = errors.New("Binding this parent would create a cycle")
+
+// CycleError is returned by Validate, collecting every cycle found in
+// the role hierarchy. The same cycle may appear more than once if it's
+// reachable from several roles.
+type CycleError[K comparable] struct {
+	Cycles [][]K
+}
+
+func (e *CycleError[K]) Error() string {
+	return fmt.Sprintf("gorbac: %d cycle(s) detected in role hierarchy", len(e.Cycles))
+}
+
+// reachableFrom reports whether `target` can be reached from `start`
+// by following the parent chain. The caller holds rbac.mutex.
+func (rbac *RBAC[K]) reachableFrom(start, target K) bool {
+	return rbac.reachableFromVisited(start, target, make(map[K]struct{}))
+}
+
+func (rbac *RBAC[K]) reachableFromVisited(start, target K, visited map[K]struct{}) bool {
+	if start == target {
+		return true
+	}
+	if _, seen := visited[start]; seen {
+		return false
+	}
+	visited[start] = empty
+	for _, parent := range rbac.parentList(start) {
+		if rbac.reachableFromVisited(parent, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate walks every role's parent chain and reports all cycles
+// found in the role hierarchy as a single *CycleError, or nil if the
+// graph is acyclic. It's mainly useful after loading a graph from an
+// untrusted store that bypassed the cycle checks in SetParent.
+func (rbac *RBAC[K]) Validate() error {
+	var cycles [][]K
+	rbac.roles.Range(func(key, _ any) bool {
+		if cycle := rbac.findCycle(key.(K)); cycle != nil {
+			cycles = append(cycles, cycle)
+		}
+		return true
+	})
+	if len(cycles) == 0 {
+		return nil
+	}
+	return &CycleError[K]{Cycles: cycles}
+}
+
+func (rbac *RBAC[K]) findCycle(start K) []K {
+	return rbac.findCycleFrom(start, []K{start}, map[K]struct{}{start: empty})
+}
+
+func (rbac *RBAC[K]) findCycleFrom(id K, path []K, onStack map[K]struct{}) []K {
+	for _, parent := range rbac.parentList(id) {
+		if _, ok := onStack[parent]; ok {
+			for i, node := range path {
+				if node == parent {
+					cycle := append([]K{}, path[i:]...)
+					return append(cycle, parent)
+				}
+			}
+		}
+		onStack[parent] = empty
+		if cycle := rbac.findCycleFrom(parent, append(path, parent), onStack); cycle != nil {
+			return cycle
+		}
+		delete(onStack, parent)
+	}
+	return nil
+}
+
+// findCycleIn returns the first cycle found in a standalone parents
+// graph (child -> set of parents), or nil if none exists. Unlike
+// findCycle it doesn't read rbac's live state, which lets callers such
+// as UnmarshalJSON validate a candidate graph before committing it.
+func findCycleIn[K comparable](parents map[K]map[K]struct{}) []K {
+	for start := range parents {
+		if cycle := findCycleInFrom(start, parents, []K{start}, map[K]struct{}{start: empty}); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func findCycleInFrom[K comparable](id K, parents map[K]map[K]struct{}, path []K, onStack map[K]struct{}) []K {
+	for parent := range parents[id] {
+		if _, ok := onStack[parent]; ok {
+			for i, node := range path {
+				if node == parent {
+					cycle := append([]K{}, path[i:]...)
+					return append(cycle, parent)
+				}
+			}
+		}
+		onStack[parent] = empty
+		if cycle := findCycleInFrom(parent, parents, append(path, parent), onStack); cycle != nil {
+			return cycle
+		}
+		delete(onStack, parent)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package gorbac
+
+import "testing"
+
+func TestSetParentRejectsCycle(t *testing.T) {
+	rbac := New[string]()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := rbac.Add(NewRole(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rbac.SetParent("b", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.SetParent("c", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rbac.SetParent("a", "c"); err != ErrCycleDetected {
+		t.Fatalf("expected ErrCycleDetected closing a->b->c->a, got %v", err)
+	}
+	if err := rbac.SetParent("a", "a"); err != ErrCycleDetected {
+		t.Fatalf("expected ErrCycleDetected for a self-parent, got %v", err)
+	}
+}
+
+func TestValidateReportsCycleFromUntrustedStore(t *testing.T) {
+	// Simulate a graph that bypassed SetParent's cycle check, e.g.
+	// loaded directly from an untrusted store.
+	store := NewMemoryStore[string]()
+	for _, id := range []string{"a", "b"} {
+		if err := store.SaveRole(NewRole(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SaveParents("a", []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveParents("b", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rbac, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rbac.Validate(); err == nil {
+		t.Fatal("expected Validate to report the a<->b cycle")
+	} else if _, ok := err.(*CycleError[string]); !ok {
+		t.Fatalf("expected a *CycleError, got %T", err)
+	}
+
+	// And IsGranted must still terminate instead of looping forever.
+	if rbac.IsGranted("a", NewPermission("x", "x"), nil) {
+		t.Error("expected no permission to be granted on an empty cyclic graph")
+	}
+}
@@ -0,0 +1,34 @@
+package gorbac
+
+import "testing"
+
+// TestLoadPersistsThroughStore proves that Load/UnmarshalJSON on a
+// store-backed RBAC persists the imported graph, so it survives a
+// restart via NewWithStore instead of only living in memory.
+func TestLoadPersistsThroughStore(t *testing.T) {
+	store := NewMemoryStore[string]()
+	rbac, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := []byte(`{
+		"roles": [{"id":"admin","permissions":[]}, {"id":"staff","permissions":[]}],
+		"inheritance": [{"child":"staff","parents":["admin"]}]
+	}`)
+	if err := rbac.UnmarshalJSON(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parents, err := restarted.GetParents("staff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != 1 || parents[0] != "admin" {
+		t.Fatalf("expected staff's imported parent to survive a restart, got %v", parents)
+	}
+}
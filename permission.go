@@ -0,0 +1,58 @@
+package gorbac
+
+// Action identifies an operation that may be performed on the resource
+// a Permission represents, e.g. Create, Read, Update or Delete.
+type Action string
+
+// Built-in actions understood by Permission.Permit.
+const (
+	Create Action = "create"
+	Read   Action = "read"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// CRUD bundles the four built-in actions together, for resources that
+// grant the full set in one call to RegisterPermission.
+var CRUD = []Action{Create, Read, Update, Delete}
+
+// Permission represents a named resource together with the set of
+// Actions that may be performed on it, e.g. "users" with Read and
+// Update but not Create or Delete.
+type Permission[K comparable] struct {
+	ID          K
+	Description string
+	actions     map[Action]struct{}
+}
+
+// NewPermission returns a Permission identified by `id` with `actions`
+// marked as allowed.
+func NewPermission[K comparable](id K, description string, actions ...Action) Permission[K] {
+	p := Permission[K]{
+		ID:          id,
+		Description: description,
+		actions:     make(map[Action]struct{}),
+	}
+	for _, action := range actions {
+		p.actions[action] = empty
+	}
+	return p
+}
+
+// Permit reports whether `action` is allowed on this Permission's
+// resource.
+func (p Permission[K]) Permit(action Action) bool {
+	_, ok := p.actions[action]
+	return ok
+}
+
+// Actions returns the set of actions allowed on this Permission's
+// resource, in no particular order. It is mainly useful to Store
+// implementations that need to serialize a Permission.
+func (p Permission[K]) Actions() []Action {
+	actions := make([]Action, 0, len(p.actions))
+	for action := range p.actions {
+		actions = append(actions, action)
+	}
+	return actions
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// functions holds the helper functions a Matcher expression can call,
+// in the style of Casbin's built-in matching functions.
+var functions = map[string]func(args []string) any{
+	"keyMatch":   func(args []string) any { return keyMatch(args[0], args[1]) },
+	"keyMatch2":  func(args []string) any { return keyMatch2(args[0], args[1]) },
+	"regexMatch": func(args []string) any { return regexMatch(args[0], args[1]) },
+}
+
+// keyMatch reports whether `key1` matches `key2`, where `key2` may end
+// in "*" to mean "anything after this prefix", e.g. keyMatch("/foo/bar",
+// "/foo/*") is true.
+func keyMatch(key1, key2 string) bool {
+	i := strings.Index(key2, "*")
+	if i == -1 {
+		return key1 == key2
+	}
+	if len(key1) < i {
+		return key1 == key2[:i]
+	}
+	return key1[:i] == key2[:i]
+}
+
+// keyMatch2 reports whether `key1` matches `key2`, where `key2` may
+// contain ":name" path parameters and "*" wildcards, e.g.
+// keyMatch2("/users/42", "/users/:id") is true.
+func keyMatch2(key1, key2 string) bool {
+	pattern := strings.ReplaceAll(key2, "/*", "/.*")
+	re := regexp.MustCompile(`:[^/]+`)
+	pattern = re.ReplaceAllString(pattern, "[^/]+")
+	return regexMatch(key1, "^"+pattern+"$")
+}
+
+// regexMatch reports whether `key1` matches the regular expression
+// `key2`.
+func regexMatch(key1, key2 string) bool {
+	matched, err := regexp.MatchString(key2, key1)
+	if err != nil {
+		return false
+	}
+	return matched
+}
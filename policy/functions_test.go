@@ -0,0 +1,28 @@
+package policy
+
+import "testing"
+
+func TestKeyMatch2(t *testing.T) {
+	tests := []struct {
+		key1, key2 string
+		want       bool
+	}{
+		{"/users/42", "/users/:id", true},
+		{"/users/42/profile", "/users/:id", false},
+		{"/users/42", "/users/:id/profile", false},
+		{"/users/42/profile", "/users/:id/profile", true},
+		{"/users", "/users/:id", false},
+		{"/users/42", "/users/*", true},
+		{"/users/42/profile", "/users/*", true},
+		{"/users", "/users/*", false},
+		{"/users/42", "/users/:id/*", false},
+		{"/users/42/profile/edit", "/users/:id/*", true},
+		{"/a/b", "/a/b", true},
+		{"/a/b", "/a/c", false},
+	}
+	for _, tt := range tests {
+		if got := keyMatch2(tt.key1, tt.key2); got != tt.want {
+			t.Errorf("keyMatch2(%q, %q) = %v, want %v", tt.key1, tt.key2, got, tt.want)
+		}
+	}
+}
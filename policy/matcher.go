@@ -0,0 +1,465 @@
+package policy
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Matcher is a compiled matcher expression, e.g.
+// `g(r.sub,p.sub) && keyMatch(r.obj,p.obj) && (r.act==p.act || p.act=="*")`.
+type Matcher struct {
+	root node
+}
+
+// NewMatcher tokenizes, parses and validates `exprStr` into a Matcher.
+// Validation catches a typo'd function name, wrong argument count, or
+// unknown field root at compile time, rather than panicking the first
+// time Match/Enforce is called on real traffic.
+func NewMatcher(exprStr string) (*Matcher, error) {
+	toks, err := tokenize(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("policy: unexpected token %q", p.peek().text)
+	}
+	if err := validate(root); err != nil {
+		return nil, err
+	}
+	return &Matcher{root: root}, nil
+}
+
+// Match evaluates the matcher against a request/policy binding.
+func (m *Matcher) Match(r RequestDef, p PolicyDef, roles RoleDef) bool {
+	b := &binding{r: r, p: p, roles: roles}
+	return toBool(eval(m.root, b))
+}
+
+// --- tokenizer ---
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokDot
+	tokComma
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokEq
+	tokNeq
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(s) {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ","})
+			i++
+		case c == '.':
+			toks = append(toks, token{tokDot, "."})
+			i++
+		case c == '&' && i+1 < len(s) && s[i+1] == '&':
+			toks = append(toks, token{tokAnd, "&&"})
+			i += 2
+		case c == '|' && i+1 < len(s) && s[i+1] == '|':
+			toks = append(toks, token{tokOr, "||"})
+			i += 2
+		case c == '=' && i+1 < len(s) && s[i+1] == '=':
+			toks = append(toks, token{tokEq, "=="})
+			i += 2
+		case c == '!' && i+1 < len(s) && s[i+1] == '=':
+			toks = append(toks, token{tokNeq, "!="})
+			i += 2
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("policy: unterminated string literal at %d", i)
+			}
+			toks = append(toks, token{tokString, s[i+1 : j]})
+			i = j + 1
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && isIdentPart(rune(s[j])) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("policy: unexpected character %q at %d", c, i)
+		}
+	}
+	toks = append(toks, token{tokEOF, ""})
+	return toks, nil
+}
+
+func isIdentStart(c rune) bool {
+	return unicode.IsLetter(c) || c == '_'
+}
+
+func isIdentPart(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_'
+}
+
+// --- parser ---
+//
+// expr   := or
+// or     := and ( '||' and )*
+// and    := eq ( '&&' eq )*
+// eq     := primary ( ('==' | '!=') primary )*
+// primary := '(' or ')' | IDENT '(' args ')' | IDENT '.' IDENT | STRING
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) atEnd() bool {
+	return p.peek().kind == tokEOF
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	if p.peek().kind != kind {
+		return token{}, fmt.Errorf("policy: expected %s, got %q", what, p.peek().text)
+	}
+	return p.advance(), nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseEq()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.advance()
+		right, err := p.parseEq()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseEq() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokEq || p.peek().kind == tokNeq {
+		op := "=="
+		if p.peek().kind == tokNeq {
+			op = "!="
+		}
+		p.advance()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	switch p.peek().kind {
+	case tokLParen:
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokString:
+		t := p.advance()
+		return &stringNode{value: t.text}, nil
+	case tokIdent:
+		name := p.advance().text
+		switch p.peek().kind {
+		case tokLParen:
+			p.advance()
+			var args []node
+			if p.peek().kind != tokRParen {
+				for {
+					arg, err := p.parseOr()
+					if err != nil {
+						return nil, err
+					}
+					args = append(args, arg)
+					if p.peek().kind != tokComma {
+						break
+					}
+					p.advance()
+				}
+			}
+			if _, err := p.expect(tokRParen, "')'"); err != nil {
+				return nil, err
+			}
+			return &callNode{name: name, args: args}, nil
+		case tokDot:
+			p.advance()
+			field, err := p.expect(tokIdent, "field name")
+			if err != nil {
+				return nil, err
+			}
+			return &fieldNode{root: name, field: field.text}, nil
+		default:
+			return nil, fmt.Errorf("policy: bare identifier %q is not a valid expression", name)
+		}
+	default:
+		return nil, fmt.Errorf("policy: unexpected token %q", p.peek().text)
+	}
+}
+
+// --- validation ---
+
+// functionArity lists every function a Matcher expression may call,
+// and how many arguments it takes. g is included alongside the
+// keyMatch family since it resolves to role-hierarchy lookups, not a
+// plain matching function.
+var functionArity = map[string]int{
+	"g":          2,
+	"keyMatch":   2,
+	"keyMatch2":  2,
+	"regexMatch": 2,
+}
+
+// validate walks the AST checking that every call targets a known
+// function with the right argument count, and every field reference
+// targets a known record (r or p) and field.
+func validate(n node) error {
+	switch v := n.(type) {
+	case *binaryNode:
+		if err := validate(v.left); err != nil {
+			return err
+		}
+		return validate(v.right)
+	case *callNode:
+		arity, ok := functionArity[v.name]
+		if !ok {
+			return fmt.Errorf("policy: unknown function %q", v.name)
+		}
+		if len(v.args) != arity {
+			return fmt.Errorf("policy: %s(...) takes %d argument(s), got %d", v.name, arity, len(v.args))
+		}
+		for _, arg := range v.args {
+			if err := validate(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *fieldNode:
+		if v.root != "r" && v.root != "p" {
+			return fmt.Errorf("policy: unknown record %q, expected r or p", v.root)
+		}
+		switch v.field {
+		case "sub", "obj", "act":
+			return nil
+		case "eft":
+			if v.root != "p" {
+				return fmt.Errorf("policy: field %q is only valid on p, not %s", v.field, v.root)
+			}
+			return nil
+		default:
+			return fmt.Errorf("policy: unknown field %s.%s", v.root, v.field)
+		}
+	case *stringNode:
+		return nil
+	default:
+		return fmt.Errorf("policy: unknown expression node %T", n)
+	}
+}
+
+// --- AST + evaluation ---
+
+type binding struct {
+	r     RequestDef
+	p     PolicyDef
+	roles RoleDef
+}
+
+type node interface {
+	eval(b *binding) any
+}
+
+func eval(n node, b *binding) any {
+	return n.eval(b)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(b *binding) any {
+	switch n.op {
+	case "&&":
+		return toBool(n.left.eval(b)) && toBool(n.right.eval(b))
+	case "||":
+		return toBool(n.left.eval(b)) || toBool(n.right.eval(b))
+	case "==":
+		return toStr(n.left.eval(b)) == toStr(n.right.eval(b))
+	case "!=":
+		return toStr(n.left.eval(b)) != toStr(n.right.eval(b))
+	}
+	panic("policy: unknown operator " + n.op)
+}
+
+type stringNode struct {
+	value string
+}
+
+func (n *stringNode) eval(*binding) any {
+	return n.value
+}
+
+type fieldNode struct {
+	root  string
+	field string
+}
+
+func (n *fieldNode) eval(b *binding) any {
+	var rec any
+	switch n.root {
+	case "r":
+		rec = b.r
+	case "p":
+		rec = b.p
+	default:
+		panic("policy: unknown record " + n.root)
+	}
+	switch v := rec.(type) {
+	case RequestDef:
+		switch n.field {
+		case "sub":
+			return v.Sub
+		case "obj":
+			return v.Obj
+		case "act":
+			return v.Act
+		}
+	case PolicyDef:
+		switch n.field {
+		case "sub":
+			return v.Sub
+		case "obj":
+			return v.Obj
+		case "act":
+			return v.Act
+		case "eft":
+			return string(v.Eft)
+		}
+	}
+	panic(fmt.Sprintf("policy: unknown field %s.%s", n.root, n.field))
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(b *binding) any {
+	if n.name == "g" {
+		if len(n.args) != 2 {
+			panic("policy: g(...) takes exactly 2 arguments")
+		}
+		sub := toStr(n.args[0].eval(b))
+		role := toStr(n.args[1].eval(b))
+		if b.roles == nil {
+			return sub == role
+		}
+		return b.roles.HasRole(sub, role)
+	}
+	args := make([]string, len(n.args))
+	for i, a := range n.args {
+		args[i] = toStr(a.eval(b))
+	}
+	fn, ok := functions[n.name]
+	if !ok {
+		panic("policy: unknown function " + n.name)
+	}
+	return fn(args)
+}
+
+func toBool(v any) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case string:
+		return t != ""
+	default:
+		return false
+	}
+}
+
+func toStr(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprint(v)
+	}
+}
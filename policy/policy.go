@@ -0,0 +1,127 @@
+/*
+Package policy implements a small Casbin-style PERM (Policy, Effect,
+Request, Matcher) engine that can sit on top of a gorbac.RBAC instance
+as a declarative alternative to writing gorbac.AssertionFunc closures.
+
+Users register PolicyDef rules of the form (sub, obj, act, eft) and a
+Matcher expression such as:
+
+	g(r.sub,p.sub) && keyMatch(r.obj,p.obj) && (r.act==p.act || p.act=="*")
+
+and call Enforcer.Enforce(sub, obj, act) to evaluate a RequestDef
+against every registered policy.
+*/
+package policy
+
+import "github.com/ErfanMomeniii/gorbac"
+
+// RequestDef describes an access request: who is asking, on what
+// object, to perform which action.
+type RequestDef struct {
+	Sub string
+	Obj string
+	Act string
+}
+
+// Effect is the outcome attached to a single PolicyDef.
+type Effect string
+
+// The two effects a PolicyDef can carry.
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// PolicyDef is a single policy rule: subject, object, action and the
+// effect it grants or denies when a Matcher matches it against a
+// request.
+type PolicyDef struct {
+	Sub string
+	Obj string
+	Act string
+	Eft Effect
+}
+
+// EffectRule selects how the effects of every matching PolicyDef
+// combine into a single decision.
+type EffectRule string
+
+const (
+	// SomeAllow grants access if at least one matching policy allows.
+	SomeAllow EffectRule = "some(where p.eft==allow)"
+	// NoDeny grants access unless at least one matching policy denies
+	// it.
+	NoDeny EffectRule = "!some(where p.eft==deny)"
+	// AllowOverDeny grants access only if some matching policy allows
+	// and none deny, so a single deny policy always wins.
+	AllowOverDeny EffectRule = "some(where p.eft==allow) && !some(where p.eft==deny)"
+)
+
+// RoleDef resolves whether one subject inherits from another. It lets
+// a Matcher's g(...) calls be backed by any role hierarchy, not just
+// gorbac's.
+type RoleDef interface {
+	HasRole(sub, role string) bool
+}
+
+// RBACRoles adapts a *gorbac.RBAC[string] to RoleDef, so g(r.sub,p.sub)
+// in a Matcher expression resolves to the RBAC's own parent traversal.
+type RBACRoles struct {
+	RBAC *gorbac.RBAC[string]
+}
+
+// HasRole reports whether `sub` is, or inherits from, `role`.
+func (r RBACRoles) HasRole(sub, role string) bool {
+	return r.RBAC.InheritsRole(sub, role)
+}
+
+// Enforcer evaluates RequestDefs against a set of PolicyDef using a
+// Matcher expression and an EffectRule.
+type Enforcer struct {
+	matcher  *Matcher
+	effect   EffectRule
+	roles    RoleDef
+	policies []PolicyDef
+}
+
+// NewEnforcer compiles `matcherExpr` and returns an Enforcer that
+// resolves g(...) calls against `roles` and combines matching policies
+// using `effect`.
+func NewEnforcer(matcherExpr string, effect EffectRule, roles RoleDef) (*Enforcer, error) {
+	matcher, err := NewMatcher(matcherExpr)
+	if err != nil {
+		return nil, err
+	}
+	return &Enforcer{matcher: matcher, effect: effect, roles: roles}, nil
+}
+
+// AddPolicy registers `p` with the enforcer.
+func (e *Enforcer) AddPolicy(p PolicyDef) {
+	e.policies = append(e.policies, p)
+}
+
+// Enforce reports whether `sub` may perform `act` on `obj`, by matching
+// every registered policy and combining the result per the Enforcer's
+// EffectRule.
+func (e *Enforcer) Enforce(sub, obj, act string) bool {
+	req := RequestDef{Sub: sub, Obj: obj, Act: act}
+	var anyAllow, anyDeny bool
+	for _, p := range e.policies {
+		if !e.matcher.Match(req, p, e.roles) {
+			continue
+		}
+		if p.Eft == Deny {
+			anyDeny = true
+		} else {
+			anyAllow = true
+		}
+	}
+	switch e.effect {
+	case SomeAllow:
+		return anyAllow
+	case NoDeny:
+		return !anyDeny
+	default:
+		return anyAllow && !anyDeny
+	}
+}
@@ -0,0 +1,51 @@
+package policy
+
+import "testing"
+
+type staticRoles map[string]string
+
+func (s staticRoles) HasRole(sub, role string) bool {
+	return sub == role || s[sub] == role
+}
+
+func TestNewMatcherRejectsUnknownFunction(t *testing.T) {
+	if _, err := NewMatcher(`keyMach(r.obj,p.obj)`); err == nil {
+		t.Error("expected an error for a typo'd function name")
+	}
+}
+
+func TestNewMatcherRejectsWrongArity(t *testing.T) {
+	if _, err := NewMatcher(`g(r.sub)`); err == nil {
+		t.Error("expected an error for g(...) called with one argument")
+	}
+}
+
+func TestNewMatcherRejectsUnknownRoot(t *testing.T) {
+	if _, err := NewMatcher(`q.sub == p.sub`); err == nil {
+		t.Error("expected an error for an unknown record root")
+	}
+}
+
+func TestEnforceAllowOverDeny(t *testing.T) {
+	roles := staticRoles{"alice": "manager"}
+	enforcer, err := NewEnforcer(
+		`g(r.sub,p.sub) && keyMatch(r.obj,p.obj) && (r.act==p.act || p.act=="*")`,
+		AllowOverDeny,
+		roles,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enforcer.AddPolicy(PolicyDef{Sub: "manager", Obj: "/users/*", Act: "read", Eft: Allow})
+	enforcer.AddPolicy(PolicyDef{Sub: "manager", Obj: "/users/42", Act: "delete", Eft: Deny})
+
+	if !enforcer.Enforce("alice", "/users/7", "read") {
+		t.Error("expected alice (manager) to be allowed to read /users/7")
+	}
+	if enforcer.Enforce("alice", "/users/42", "delete") {
+		t.Error("expected the explicit deny policy to win over the allow wildcard")
+	}
+	if enforcer.Enforce("bob", "/users/7", "read") {
+		t.Error("expected bob, who has no role, to be denied")
+	}
+}
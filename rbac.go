@@ -33,19 +33,74 @@ var (
 type AssertionFunc[K comparable] func(*RBAC[K], K, Permission[K]) bool
 
 // RBAC object, in most cases it should be used as a singleton.
+//
+// Roles and their parent edges live in sync.Map so that read-heavy
+// IsGranted calls never contend on a global lock; mutex only
+// serializes the handful of structural mutations (Add, Remove,
+// SetParent, ...) that touch more than one entry at a time.
 type RBAC[K comparable] struct {
-	mutex   sync.RWMutex
-	roles   Roles[K]
-	parents map[K]map[K]struct{}
+	mutex   sync.Mutex
+	roles   sync.Map // K -> Role[K]
+	parents sync.Map // K -> *sync.Map (K -> struct{})
+	store   Store[K]
 }
 
 // New returns a RBAC structure.
 // The default role structure will be used.
 func New[K comparable]() *RBAC[K] {
-	return &RBAC[K]{
-		roles:   make(Roles[K]),
-		parents: make(map[K]map[K]struct{}),
+	return &RBAC[K]{}
+}
+
+// NewWithStore returns a RBAC structure whose mutating methods persist
+// through `store`. The in-memory maps are hydrated from whatever the
+// store already holds, so RBAC state survives process restarts.
+func NewWithStore[K comparable](store Store[K]) (*RBAC[K], error) {
+	roles, parents, err := store.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	rbac := &RBAC[K]{store: store}
+	for id, r := range roles {
+		rbac.roles.Store(id, r)
+	}
+	for id, ps := range parents {
+		set := &sync.Map{}
+		for parent := range ps {
+			set.Store(parent, empty)
+		}
+		rbac.parents.Store(id, set)
+	}
+	return rbac, nil
+}
+
+// RegisterPermission is a constructor helper for building a Permission
+// identified by `id`, covering `actions` on the named resource
+// `description`. It doesn't touch the RBAC instance; the returned
+// Permission still needs to be added to a Role's Permissions (e.g. via
+// NewRole and Add) before IsGranted/IsGrantedAction can see it.
+func (rbac *RBAC[K]) RegisterPermission(id K, description string, actions ...Action) Permission[K] {
+	return NewPermission(id, description, actions...)
+}
+
+// parentSet returns the *sync.Map holding `id`'s parents, creating it
+// if it doesn't exist yet.
+func (rbac *RBAC[K]) parentSet(id K) *sync.Map {
+	v, _ := rbac.parents.LoadOrStore(id, &sync.Map{})
+	return v.(*sync.Map)
+}
+
+// parentList returns the parent IDs of `id`, in no particular order.
+func (rbac *RBAC[K]) parentList(id K) []K {
+	v, ok := rbac.parents.Load(id)
+	if !ok {
+		return nil
 	}
+	var ids []K
+	v.(*sync.Map).Range(func(key, _ any) bool {
+		ids = append(ids, key.(K))
+		return true
+	})
+	return ids
 }
 
 // SetParents bind `parents` to the role `id`.
@@ -54,19 +109,36 @@ func New[K comparable]() *RBAC[K] {
 func (rbac *RBAC[K]) SetParents(id K, parents []K) error {
 	rbac.mutex.Lock()
 	defer rbac.mutex.Unlock()
-	if _, ok := rbac.roles[id]; !ok {
+	if _, ok := rbac.roles.Load(id); !ok {
 		return ErrRoleNotExist
 	}
 	for _, parent := range parents {
-		if _, ok := rbac.roles[parent]; !ok {
+		if _, ok := rbac.roles.Load(parent); !ok {
 			return ErrRoleNotExist
 		}
+		if rbac.reachableFrom(parent, id) {
+			return ErrCycleDetected
+		}
 	}
-	if _, ok := rbac.parents[id]; !ok {
-		rbac.parents[id] = make(map[K]struct{})
+	if rbac.store != nil {
+		merged := make(map[K]struct{})
+		for _, p := range rbac.parentList(id) {
+			merged[p] = empty
+		}
+		for _, p := range parents {
+			merged[p] = empty
+		}
+		list := make([]K, 0, len(merged))
+		for p := range merged {
+			list = append(list, p)
+		}
+		if err := rbac.store.SaveParents(id, list); err != nil {
+			return err
+		}
 	}
+	set := rbac.parentSet(id)
 	for _, parent := range parents {
-		rbac.parents[id][parent] = empty
+		set.Store(parent, empty)
 	}
 	return nil
 }
@@ -76,20 +148,10 @@ func (rbac *RBAC[K]) SetParents(id K, parents []K) error {
 // Or the role doesn't have any parents,
 // a nil slice will be returned.
 func (rbac *RBAC[K]) GetParents(id K) ([]K, error) {
-	rbac.mutex.Lock()
-	defer rbac.mutex.Unlock()
-	if _, ok := rbac.roles[id]; !ok {
+	if _, ok := rbac.roles.Load(id); !ok {
 		return nil, ErrRoleNotExist
 	}
-	ids, ok := rbac.parents[id]
-	if !ok {
-		return nil, nil
-	}
-	var parents []K
-	for parent := range ids {
-		parents = append(parents, parent)
-	}
-	return parents, nil
+	return rbac.parentList(id), nil
 }
 
 // SetParent bind the `parent` to the role `id`.
@@ -98,17 +160,22 @@ func (rbac *RBAC[K]) GetParents(id K) ([]K, error) {
 func (rbac *RBAC[K]) SetParent(id K, parent K) error {
 	rbac.mutex.Lock()
 	defer rbac.mutex.Unlock()
-	if _, ok := rbac.roles[id]; !ok {
+	if _, ok := rbac.roles.Load(id); !ok {
 		return ErrRoleNotExist
 	}
-	if _, ok := rbac.roles[parent]; !ok {
+	if _, ok := rbac.roles.Load(parent); !ok {
 		return ErrRoleNotExist
 	}
-	if _, ok := rbac.parents[id]; !ok {
-		rbac.parents[id] = make(map[K]struct{})
+	if rbac.reachableFrom(parent, id) {
+		return ErrCycleDetected
 	}
-	var empty struct{}
-	rbac.parents[id][parent] = empty
+	if rbac.store != nil {
+		list := append(rbac.parentList(id), parent)
+		if err := rbac.store.SaveParents(id, list); err != nil {
+			return err
+		}
+	}
+	rbac.parentSet(id).Store(parent, empty)
 	return nil
 }
 
@@ -118,74 +185,166 @@ func (rbac *RBAC[K]) SetParent(id K, parent K) error {
 func (rbac *RBAC[K]) RemoveParent(id K, parent K) error {
 	rbac.mutex.Lock()
 	defer rbac.mutex.Unlock()
-	if _, ok := rbac.roles[id]; !ok {
+	if _, ok := rbac.roles.Load(id); !ok {
 		return ErrRoleNotExist
 	}
-	if _, ok := rbac.roles[parent]; !ok {
+	if _, ok := rbac.roles.Load(parent); !ok {
 		return ErrRoleNotExist
 	}
-	delete(rbac.parents[id], parent)
+	if rbac.store != nil {
+		current := rbac.parentList(id)
+		list := make([]K, 0, len(current))
+		for _, p := range current {
+			if p != parent {
+				list = append(list, p)
+			}
+		}
+		if err := rbac.store.SaveParents(id, list); err != nil {
+			return err
+		}
+	}
+	if v, ok := rbac.parents.Load(id); ok {
+		v.(*sync.Map).Delete(parent)
+	}
 	return nil
 }
 
 // Add a role `r`.
 func (rbac *RBAC[K]) Add(r Role[K]) (err error) {
 	rbac.mutex.Lock()
-	if _, ok := rbac.roles[r.ID]; !ok {
-		rbac.roles[r.ID] = r
-	} else {
-		err = ErrRoleExist
+	defer rbac.mutex.Unlock()
+	if _, ok := rbac.roles.Load(r.ID); ok {
+		return ErrRoleExist
 	}
-	rbac.mutex.Unlock()
-	return
+	if rbac.store != nil {
+		if err = rbac.store.SaveRole(r); err != nil {
+			return err
+		}
+	}
+	rbac.roles.Store(r.ID, r)
+	return nil
 }
 
 // Remove the role by `id`.
 func (rbac *RBAC[K]) Remove(id K) (err error) {
 	rbac.mutex.Lock()
-	if _, ok := rbac.roles[id]; ok {
-		delete(rbac.roles, id)
-		for rid, parents := range rbac.parents {
-			if rid == id {
-				delete(rbac.parents, rid)
-				continue
-			}
-			for parent := range parents {
-				if parent == id {
-					delete(rbac.parents[rid], id)
-					break
-				}
-			}
+	defer rbac.mutex.Unlock()
+	if _, ok := rbac.roles.Load(id); !ok {
+		return ErrRoleNotExist
+	}
+	if rbac.store != nil {
+		if err = rbac.store.DeleteRole(id); err != nil {
+			return err
 		}
-	} else {
-		err = ErrRoleNotExist
 	}
-	rbac.mutex.Unlock()
-	return
+	rbac.roles.Delete(id)
+	rbac.parents.Delete(id)
+	rbac.parents.Range(func(_, value any) bool {
+		value.(*sync.Map).Delete(id)
+		return true
+	})
+	return nil
 }
 
-// Get the role by `id` and a slice of its parents id.
+// Get the role by `id` and a slice of its parents id. The returned Role
+// is a copy; mutating its Permissions does not affect the live RBAC
+// state and is safe to do concurrently with other RBAC calls.
 func (rbac *RBAC[K]) Get(id K) (r Role[K], parents []K, err error) {
-	rbac.mutex.RLock()
-	var ok bool
-	if r, ok = rbac.roles[id]; ok {
-		for parent := range rbac.parents[id] {
-			parents = append(parents, parent)
-		}
-	} else {
+	v, ok := rbac.roles.Load(id)
+	if !ok {
 		err = ErrRoleNotExist
+		return
 	}
-	rbac.mutex.RUnlock()
+	r = v.(Role[K]).Clone()
+	parents = rbac.parentList(id)
 	return
 }
 
+// Walk calls `fn` for every role together with its parent IDs, without
+// holding a global lock. Iteration order is unspecified. Walk stops as
+// soon as `fn` returns false. Each Role passed to `fn` is its own copy,
+// safe to mutate without racing the live RBAC state.
+func (rbac *RBAC[K]) Walk(fn func(Role[K], []K) bool) {
+	rbac.roles.Range(func(key, value any) bool {
+		id := key.(K)
+		return fn(value.(Role[K]).Clone(), rbac.parentList(id))
+	})
+}
+
+// replaceState swaps the whole role and parent graph for `roles` and
+// `parents` while holding rbac.mutex, so other mutations and Snapshot
+// never interleave with it. Lock-free readers such as IsGranted, Get
+// and Walk may observe the graph part-way through the swap. It's used
+// by UnmarshalJSON/Load to apply a graph that has already been fully
+// validated, so the RBAC instance never ends up in a partially-loaded
+// state.
+//
+// If the RBAC instance is store-backed, the new graph is persisted
+// first via a single Store.ReplaceAll call, so a store failure rolls
+// back to the store's pre-existing graph instead of leaving it with a
+// mix of old and new roles/parents. Only once that succeeds are the
+// in-memory maps replaced.
+func (rbac *RBAC[K]) replaceState(roles Roles[K], parents map[K]map[K]struct{}) error {
+	rbac.mutex.Lock()
+	defer rbac.mutex.Unlock()
+
+	if rbac.store != nil {
+		lists := make(map[K][]K, len(parents))
+		for id, ps := range parents {
+			list := make([]K, 0, len(ps))
+			for p := range ps {
+				list = append(list, p)
+			}
+			lists[id] = list
+		}
+		if err := rbac.store.ReplaceAll(roles, lists); err != nil {
+			return err
+		}
+	}
+
+	rbac.roles.Range(func(key, _ any) bool {
+		rbac.roles.Delete(key)
+		return true
+	})
+	rbac.parents.Range(func(key, _ any) bool {
+		rbac.parents.Delete(key)
+		return true
+	})
+	for id, r := range roles {
+		rbac.roles.Store(id, r)
+	}
+	for id, ps := range parents {
+		set := &sync.Map{}
+		for p := range ps {
+			set.Store(p, empty)
+		}
+		rbac.parents.Store(id, set)
+	}
+	return nil
+}
+
+// Snapshot returns a point-in-time, read-only copy of the RBAC graph,
+// suitable for lock-free permission checks during a burst of traffic.
+func (rbac *RBAC[K]) Snapshot() RBACState[K] {
+	rbac.mutex.Lock()
+	defer rbac.mutex.Unlock()
+	roles := make(Roles[K])
+	rbac.roles.Range(func(key, value any) bool {
+		roles[key.(K)] = value.(Role[K]).Clone()
+		return true
+	})
+	parents := make(map[K][]K, len(roles))
+	rbac.parents.Range(func(key, _ any) bool {
+		id := key.(K)
+		parents[id] = rbac.parentList(id)
+		return true
+	})
+	return RBACState[K]{Roles: roles, Parents: parents}
+}
+
 // IsGranted tests if the role `id` has Permission `p` with the condition `assert`.
-func (rbac *RBAC[K]) IsGranted(id K, p Permission[K],
-assert AssertionFunc[K]) (ok bool) {
-	rbac.mutex.RLock()
-	ok = rbac.isGranted(id, p, assert)
-	rbac.mutex.RUnlock()
-	return
+func (rbac *RBAC[K]) IsGranted(id K, p Permission[K], assert AssertionFunc[K]) bool {
+	return rbac.isGranted(id, p, assert)
 }
 
 func (rbac *RBAC[K]) isGranted(id K, p Permission[K], assert AssertionFunc[K]) bool {
@@ -196,18 +355,88 @@ func (rbac *RBAC[K]) isGranted(id K, p Permission[K], assert AssertionFunc[K]) b
 }
 
 func (rbac *RBAC[K]) recursionCheck(id K, p Permission[K]) bool {
-	if role, ok := rbac.roles[id]; ok {
-		if role.Permit(p) {
+	return rbac.recursionCheckVisited(id, p, make(map[K]struct{}))
+}
+
+// recursionCheckVisited guards against re-visiting a role, so a
+// pathological graph (e.g. loaded from an untrusted store that
+// bypassed SetParent's cycle check) can't cause exponential or
+// unbounded traversal.
+func (rbac *RBAC[K]) recursionCheckVisited(id K, p Permission[K], visited map[K]struct{}) bool {
+	if _, seen := visited[id]; seen {
+		return false
+	}
+	visited[id] = empty
+	v, ok := rbac.roles.Load(id)
+	if !ok {
+		return false
+	}
+	if v.(Role[K]).Permit(p) {
+		return true
+	}
+	for _, pID := range rbac.parentList(id) {
+		if rbac.recursionCheckVisited(pID, p, visited) {
 			return true
 		}
-		if parents, ok := rbac.parents[id]; ok {
-			for pID := range parents {
-				if _, ok := rbac.roles[pID]; ok {
-					if rbac.recursionCheck(pID, p) {
-						return true
-					}
-				}
-			}
+	}
+	return false
+}
+
+// IsGrantedAction tests if the role `id` has `action` allowed on the
+// Permission `permID`, walking the parent chain the same way IsGranted
+// does. This lets a role be granted partial access to a resource, e.g.
+// Read but not Delete on "users".
+func (rbac *RBAC[K]) IsGrantedAction(id K, permID K, action Action) bool {
+	return rbac.recursionCheckAction(id, permID, action)
+}
+
+// InheritsRole reports whether `id` is `ancestor` or inherits from it
+// through the parent chain. It is the role-only counterpart of
+// IsGranted, for callers that only care about hierarchy, not
+// permissions, such as the policy subpackage's g() matcher function.
+func (rbac *RBAC[K]) InheritsRole(id K, ancestor K) bool {
+	return rbac.recursionCheckRole(id, ancestor)
+}
+
+func (rbac *RBAC[K]) recursionCheckRole(id K, ancestor K) bool {
+	return rbac.recursionCheckRoleVisited(id, ancestor, make(map[K]struct{}))
+}
+
+func (rbac *RBAC[K]) recursionCheckRoleVisited(id K, ancestor K, visited map[K]struct{}) bool {
+	if id == ancestor {
+		return true
+	}
+	if _, seen := visited[id]; seen {
+		return false
+	}
+	visited[id] = empty
+	for _, pID := range rbac.parentList(id) {
+		if rbac.recursionCheckRoleVisited(pID, ancestor, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rbac *RBAC[K]) recursionCheckAction(id K, permID K, action Action) bool {
+	return rbac.recursionCheckActionVisited(id, permID, action, make(map[K]struct{}))
+}
+
+func (rbac *RBAC[K]) recursionCheckActionVisited(id K, permID K, action Action, visited map[K]struct{}) bool {
+	if _, seen := visited[id]; seen {
+		return false
+	}
+	visited[id] = empty
+	v, ok := rbac.roles.Load(id)
+	if !ok {
+		return false
+	}
+	if p, ok := v.(Role[K]).Permissions[permID]; ok && p.Permit(action) {
+		return true
+	}
+	for _, pID := range rbac.parentList(id) {
+		if rbac.recursionCheckActionVisited(pID, permID, action, visited) {
+			return true
 		}
 	}
 	return false
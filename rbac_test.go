@@ -0,0 +1,77 @@
+package gorbac
+
+import (
+	"testing"
+)
+
+func newBenchRBAC(b *testing.B) *RBAC[string] {
+	b.Helper()
+	rbac := New[string]()
+	perm := NewPermission("read-users", "users", Read)
+	for _, id := range []string{"admin", "manager", "staff"} {
+		role := NewRole(id)
+		role.Permissions[perm.ID] = perm
+		if err := rbac.Add(role); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := rbac.SetParent("manager", "admin"); err != nil {
+		b.Fatal(err)
+	}
+	if err := rbac.SetParent("staff", "manager"); err != nil {
+		b.Fatal(err)
+	}
+	return rbac
+}
+
+// BenchmarkIsGrantedParallel exercises the read-heavy path IsGranted
+// takes under concurrent load, which is what motivated moving the role
+// and parent storage to sync.Map.
+func BenchmarkIsGrantedParallel(b *testing.B) {
+	rbac := newBenchRBAC(b)
+	perm := NewPermission("read-users", "users", Read)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if !rbac.IsGranted("staff", perm, nil) {
+				b.Fatal("expected staff to be granted read-users")
+			}
+		}
+	})
+}
+
+func BenchmarkIsGranted(b *testing.B) {
+	rbac := newBenchRBAC(b)
+	perm := NewPermission("read-users", "users", Read)
+	for i := 0; i < b.N; i++ {
+		if !rbac.IsGranted("staff", perm, nil) {
+			b.Fatal("expected staff to be granted read-users")
+		}
+	}
+}
+
+// TestIsGrantedActionChecksRoleNotRegistry makes sure IsGrantedAction
+// only sees actions that were actually granted to the role, and that
+// RegisterPermission is just a constructor helper: calling it alone
+// doesn't grant anything.
+func TestIsGrantedActionChecksRoleNotRegistry(t *testing.T) {
+	rbac := New[string]()
+	admin := NewRole("admin")
+	admin.Permissions["users"] = NewPermission("users", "users", Read, Update)
+	if err := rbac.Add(admin); err != nil {
+		t.Fatal(err)
+	}
+
+	if !rbac.IsGrantedAction("admin", "users", Read) {
+		t.Error("expected admin to have Read on users")
+	}
+	if rbac.IsGrantedAction("admin", "users", Delete) {
+		t.Error("expected admin not to have Delete on users")
+	}
+
+	// Registering a permission does not, on its own, grant it to any
+	// role: it must still be added to a Role's Permissions.
+	rbac.RegisterPermission("invoices", "invoices", Read, Delete)
+	if rbac.IsGrantedAction("admin", "invoices", Read) {
+		t.Error("RegisterPermission alone should not grant anything")
+	}
+}
@@ -0,0 +1,37 @@
+package gorbac
+
+// Role represents a named role that can be granted to identities and
+// that holds a set of Permissions.
+type Role[K comparable] struct {
+	ID          K
+	Permissions map[K]Permission[K]
+}
+
+// NewRole returns a Role identified by `id` with an empty permission set.
+func NewRole[K comparable](id K) Role[K] {
+	return Role[K]{
+		ID:          id,
+		Permissions: make(map[K]Permission[K]),
+	}
+}
+
+// Permit reports whether the role holds Permission `p`.
+func (r Role[K]) Permit(p Permission[K]) bool {
+	_, ok := r.Permissions[p.ID]
+	return ok
+}
+
+// Clone returns a copy of the role with its own Permissions map, so a
+// caller that mutates the returned Permissions (or holds onto it
+// across goroutines) can't race a concurrent RBAC read or write of the
+// original role's map.
+func (r Role[K]) Clone() Role[K] {
+	permissions := make(map[K]Permission[K], len(r.Permissions))
+	for id, p := range r.Permissions {
+		permissions[id] = p
+	}
+	return Role[K]{ID: r.ID, Permissions: permissions}
+}
+
+// Roles is a collection of Role indexed by their ID.
+type Roles[K comparable] map[K]Role[K]
@@ -0,0 +1,42 @@
+package gorbac
+
+import "testing"
+
+// TestGetReturnsIndependentRole proves that mutating the Permissions map
+// returned by Get/Walk/Snapshot cannot race or corrupt the live RBAC
+// state, closing the data race fixed by Role.Clone.
+func TestGetReturnsIndependentRole(t *testing.T) {
+	rbac := New[string]()
+	if err := rbac.Add(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+
+	role, _, err := rbac.Get("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm := NewPermission("read-users", "users", Read)
+	role.Permissions[perm.ID] = perm
+
+	live, _, err := rbac.Get("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.Permit(perm) {
+		t.Error("expected mutating a Get'd Role's Permissions not to affect the live RBAC state")
+	}
+
+	rbac.Walk(func(r Role[string], _ []string) bool {
+		r.Permissions[perm.ID] = perm
+		return true
+	})
+	if live, _, _ := rbac.Get("admin"); live.Permit(perm) {
+		t.Error("expected mutating a Walk'd Role's Permissions not to affect the live RBAC state")
+	}
+
+	snap := rbac.Snapshot()
+	snap.Roles["admin"].Permissions[perm.ID] = perm
+	if live, _, _ := rbac.Get("admin"); live.Permit(perm) {
+		t.Error("expected mutating a Snapshot Role's Permissions not to affect the live RBAC state")
+	}
+}
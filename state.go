@@ -0,0 +1,36 @@
+package gorbac
+
+// RBACState is an immutable, point-in-time copy of an RBAC graph
+// returned by RBAC.Snapshot. It holds its own copies of the role and
+// parent maps, so it can be read from any number of goroutines without
+// further locking, even while the live RBAC graph keeps mutating.
+type RBACState[K comparable] struct {
+	Roles   Roles[K]
+	Parents map[K][]K
+}
+
+// IsGranted tests if the role `id` has Permission `p`, evaluated
+// against this frozen snapshot rather than the live RBAC graph.
+func (s RBACState[K]) IsGranted(id K, p Permission[K]) bool {
+	return s.recursionCheck(id, p, make(map[K]struct{}))
+}
+
+func (s RBACState[K]) recursionCheck(id K, p Permission[K], visited map[K]struct{}) bool {
+	if _, seen := visited[id]; seen {
+		return false
+	}
+	visited[id] = empty
+	role, ok := s.Roles[id]
+	if !ok {
+		return false
+	}
+	if role.Permit(p) {
+		return true
+	}
+	for _, parent := range s.Parents[id] {
+		if s.recursionCheck(parent, p, visited) {
+			return true
+		}
+	}
+	return false
+}
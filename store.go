@@ -0,0 +1,36 @@
+package gorbac
+
+import "errors"
+
+// ErrStoreRoleNotFound is returned by a Store when asked to load or
+// delete a role it doesn't hold.
+var ErrStoreRoleNotFound = errors.New("gorbac: role not found in store")
+
+// Store is implemented by persistence backends that keep an RBAC graph
+// alive across process restarts. RBAC methods that mutate state persist
+// through the configured Store before the in-memory maps are updated,
+// so a failed write leaves the in-memory state untouched.
+type Store[K comparable] interface {
+	// SaveRole creates or overwrites the role `r`, including the
+	// permissions it carries.
+	SaveRole(r Role[K]) error
+	// LoadRole returns the role `id`, or ErrStoreRoleNotFound if it
+	// doesn't exist.
+	LoadRole(id K) (Role[K], error)
+	// DeleteRole removes the role `id` and any parent edges that
+	// mention it.
+	DeleteRole(id K) error
+	// SaveParents replaces the full set of parents bound to the role
+	// `id`.
+	SaveParents(id K, parents []K) error
+	// LoadAll returns every role and the complete parent graph, for
+	// hydrating an RBAC instance on startup.
+	LoadAll() (Roles[K], map[K]map[K]struct{}, error)
+	// ReplaceAll atomically discards every role and parent edge the
+	// store holds and replaces them with `roles` and `parents`. It
+	// backs RBAC.UnmarshalJSON/Load, which must persist a whole new
+	// graph as a single unit: applying it role-by-role through
+	// SaveRole/DeleteRole/SaveParents could leave the store with a mix
+	// of old and new state if it failed partway through.
+	ReplaceAll(roles Roles[K], parents map[K][]K) error
+}
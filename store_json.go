@@ -0,0 +1,200 @@
+package gorbac
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+)
+
+// jsonPermission and jsonRole are the on-disk shapes used by JSONStore;
+// they exist because Permission and Role carry unexported state that
+// the standard json package can't reach directly.
+type jsonPermission[K comparable] struct {
+	ID          K        `json:"id"`
+	Description string   `json:"description"`
+	Actions     []Action `json:"actions"`
+}
+
+type jsonRole[K comparable] struct {
+	ID          K                   `json:"id"`
+	Permissions []jsonPermission[K] `json:"permissions"`
+}
+
+type jsonStoreData[K comparable] struct {
+	Roles   []jsonRole[K] `json:"roles"`
+	Parents map[K][]K     `json:"parents"`
+}
+
+// JSONStore is a Store backed by a single JSON file on disk. It is
+// meant for small deployments and tests; every mutation reads, edits
+// and rewrites the whole file under its own lock.
+type JSONStore[K comparable] struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewJSONStore returns a JSONStore persisting to `path`. The file is
+// created on the first write if it doesn't already exist.
+func NewJSONStore[K comparable](path string) *JSONStore[K] {
+	return &JSONStore[K]{path: path}
+}
+
+func (s *JSONStore[K]) read() (jsonStoreData[K], error) {
+	data := jsonStoreData[K]{Parents: make(map[K][]K)}
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return data, err
+	}
+	if len(b) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, err
+	}
+	if data.Parents == nil {
+		data.Parents = make(map[K][]K)
+	}
+	return data, nil
+}
+
+func (s *JSONStore[K]) write(data jsonStoreData[K]) error {
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func toJSONRole[K comparable](r Role[K]) jsonRole[K] {
+	jr := jsonRole[K]{ID: r.ID}
+	for _, p := range r.Permissions {
+		jr.Permissions = append(jr.Permissions, jsonPermission[K]{
+			ID:          p.ID,
+			Description: p.Description,
+			Actions:     p.Actions(),
+		})
+	}
+	return jr
+}
+
+func fromJSONRole[K comparable](jr jsonRole[K]) Role[K] {
+	r := NewRole(jr.ID)
+	for _, jp := range jr.Permissions {
+		r.Permissions[jp.ID] = NewPermission(jp.ID, jp.Description, jp.Actions...)
+	}
+	return r
+}
+
+func (s *JSONStore[K]) SaveRole(r Role[K]) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	jr := toJSONRole(r)
+	for i, existing := range data.Roles {
+		if existing.ID == r.ID {
+			data.Roles[i] = jr
+			return s.write(data)
+		}
+	}
+	data.Roles = append(data.Roles, jr)
+	return s.write(data)
+}
+
+func (s *JSONStore[K]) LoadRole(id K) (Role[K], error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return Role[K]{}, err
+	}
+	for _, jr := range data.Roles {
+		if jr.ID == id {
+			return fromJSONRole(jr), nil
+		}
+	}
+	return Role[K]{}, ErrStoreRoleNotFound
+}
+
+func (s *JSONStore[K]) DeleteRole(id K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	for i, jr := range data.Roles {
+		if jr.ID == id {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+			break
+		}
+	}
+	delete(data.Parents, id)
+	for child, parents := range data.Parents {
+		filtered := parents[:0]
+		for _, p := range parents {
+			if p != id {
+				filtered = append(filtered, p)
+			}
+		}
+		data.Parents[child] = filtered
+	}
+	return s.write(data)
+}
+
+func (s *JSONStore[K]) SaveParents(id K, parents []K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+	data.Parents[id] = parents
+	return s.write(data)
+}
+
+func (s *JSONStore[K]) LoadAll() (Roles[K], map[K]map[K]struct{}, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, err := s.read()
+	if err != nil {
+		return nil, nil, err
+	}
+	roles := make(Roles[K], len(data.Roles))
+	for _, jr := range data.Roles {
+		roles[jr.ID] = fromJSONRole(jr)
+	}
+	parents := make(map[K]map[K]struct{}, len(data.Parents))
+	for id, ps := range data.Parents {
+		set := make(map[K]struct{}, len(ps))
+		for _, p := range ps {
+			set[p] = empty
+		}
+		parents[id] = set
+	}
+	return roles, parents, nil
+}
+
+func (s *JSONStore[K]) ReplaceAll(roles Roles[K], parents map[K][]K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data := jsonStoreData[K]{Parents: make(map[K][]K, len(parents))}
+	for _, r := range roles {
+		data.Roles = append(data.Roles, toJSONRole(r))
+	}
+	for id, ps := range parents {
+		data.Parents[id] = ps
+	}
+	return s.write(data)
+}
@@ -0,0 +1,98 @@
+package gorbac
+
+import "sync"
+
+// MemoryStore is an in-memory Store implementation. It adds nothing
+// over RBAC's own maps on its own, but is useful for tests that want
+// to exercise the Store-backed code paths, or as a starting point for
+// a custom backend.
+type MemoryStore[K comparable] struct {
+	mutex   sync.RWMutex
+	roles   Roles[K]
+	parents map[K]map[K]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore[K comparable]() *MemoryStore[K] {
+	return &MemoryStore[K]{
+		roles:   make(Roles[K]),
+		parents: make(map[K]map[K]struct{}),
+	}
+}
+
+func (s *MemoryStore[K]) SaveRole(r Role[K]) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.roles[r.ID] = r
+	return nil
+}
+
+func (s *MemoryStore[K]) LoadRole(id K) (Role[K], error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	r, ok := s.roles[id]
+	if !ok {
+		return Role[K]{}, ErrStoreRoleNotFound
+	}
+	return r, nil
+}
+
+func (s *MemoryStore[K]) DeleteRole(id K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.roles, id)
+	delete(s.parents, id)
+	for _, set := range s.parents {
+		delete(set, id)
+	}
+	return nil
+}
+
+func (s *MemoryStore[K]) SaveParents(id K, parents []K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	set := make(map[K]struct{}, len(parents))
+	for _, p := range parents {
+		set[p] = empty
+	}
+	s.parents[id] = set
+	return nil
+}
+
+func (s *MemoryStore[K]) LoadAll() (Roles[K], map[K]map[K]struct{}, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	roles := make(Roles[K], len(s.roles))
+	for id, r := range s.roles {
+		roles[id] = r
+	}
+	parents := make(map[K]map[K]struct{}, len(s.parents))
+	for id, ps := range s.parents {
+		set := make(map[K]struct{}, len(ps))
+		for p := range ps {
+			set[p] = empty
+		}
+		parents[id] = set
+	}
+	return roles, parents, nil
+}
+
+func (s *MemoryStore[K]) ReplaceAll(roles Roles[K], parents map[K][]K) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	newRoles := make(Roles[K], len(roles))
+	for id, r := range roles {
+		newRoles[id] = r
+	}
+	newParents := make(map[K]map[K]struct{}, len(parents))
+	for id, ps := range parents {
+		set := make(map[K]struct{}, len(ps))
+		for _, p := range ps {
+			set[p] = empty
+		}
+		newParents[id] = set
+	}
+	s.roles = newRoles
+	s.parents = newParents
+	return nil
+}
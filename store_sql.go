@@ -0,0 +1,281 @@
+package gorbac
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// SQLStore is a Store backed by database/sql, modeled on a roles table
+// plus a role_permission join table that holds the resource
+// description and allowed actions for each (role, permission) pair.
+// The description/actions are scoped per role rather than keyed only
+// by permission_id, because two roles can hold the same permission ID
+// with different action sets (e.g. viewer=Read, editor=Read+Update on
+// "users"); a table keyed by permission_id alone would let one role's
+// save silently rewrite another role's actions. Parent edges live in a
+// third join table, role_parent.
+//
+// K must be representable as the TEXT primary key used by the schema;
+// `keyString` renders K to that representation and `keyParse` is its
+// inverse, used when rows are read back.
+type SQLStore[K comparable] struct {
+	db        *sql.DB
+	keyString func(K) string
+	keyParse  func(string) (K, error)
+}
+
+// NewSQLStore returns a SQLStore using `db`. Call Schema once to create
+// the tables it needs before using it with NewWithStore.
+func NewSQLStore[K comparable](db *sql.DB, keyString func(K) string, keyParse func(string) (K, error)) *SQLStore[K] {
+	return &SQLStore[K]{db: db, keyString: keyString, keyParse: keyParse}
+}
+
+// Schema creates the roles, role_parent and role_permission tables if
+// they do not already exist.
+func (s *SQLStore[K]) Schema() error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS roles (
+			id TEXT PRIMARY KEY
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_parent (
+			role_id TEXT NOT NULL,
+			parent_id TEXT NOT NULL,
+			PRIMARY KEY (role_id, parent_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_permission (
+			role_id TEXT NOT NULL,
+			permission_id TEXT NOT NULL,
+			description TEXT NOT NULL,
+			actions TEXT NOT NULL,
+			PRIMARY KEY (role_id, permission_id)
+		)`,
+	}
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore[K]) SaveRole(r Role[K]) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	id := s.keyString(r.ID)
+	if _, err := tx.Exec(`INSERT INTO roles (id) VALUES (?) ON CONFLICT (id) DO NOTHING`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM role_permission WHERE role_id = ?`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, p := range r.Permissions {
+		permID := s.keyString(p.ID)
+		actions, err := json.Marshal(p.Actions())
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO role_permission (role_id, permission_id, description, actions) VALUES (?, ?, ?, ?)`,
+			id, permID, p.Description, string(actions),
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore[K]) LoadRole(id K) (Role[K], error) {
+	idStr := s.keyString(id)
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM roles WHERE id = ?)`, idStr).Scan(&exists); err != nil {
+		return Role[K]{}, err
+	}
+	if !exists {
+		return Role[K]{}, ErrStoreRoleNotFound
+	}
+	role := NewRole(id)
+	rows, err := s.db.Query(`
+		SELECT permission_id, description, actions
+		FROM role_permission
+		WHERE role_id = ?`, idStr)
+	if err != nil {
+		return Role[K]{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var permIDStr, description, actionsJSON string
+		if err := rows.Scan(&permIDStr, &description, &actionsJSON); err != nil {
+			return Role[K]{}, err
+		}
+		permID, err := s.keyParse(permIDStr)
+		if err != nil {
+			return Role[K]{}, err
+		}
+		var actions []Action
+		if err := json.Unmarshal([]byte(actionsJSON), &actions); err != nil {
+			return Role[K]{}, err
+		}
+		role.Permissions[permID] = NewPermission(permID, description, actions...)
+	}
+	return role, rows.Err()
+}
+
+func (s *SQLStore[K]) DeleteRole(id K) error {
+	idStr := s.keyString(id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM role_permission WHERE role_id = ?`, idStr); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM role_parent WHERE role_id = ? OR parent_id = ?`, idStr, idStr); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM roles WHERE id = ?`, idStr); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore[K]) SaveParents(id K, parents []K) error {
+	idStr := s.keyString(id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM role_parent WHERE role_id = ?`, idStr); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, parent := range parents {
+		if _, err := tx.Exec(`INSERT INTO role_parent (role_id, parent_id) VALUES (?, ?)`, idStr, s.keyString(parent)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore[K]) LoadAll() (Roles[K], map[K]map[K]struct{}, error) {
+	idRows, err := s.db.Query(`SELECT id FROM roles`)
+	if err != nil {
+		return nil, nil, err
+	}
+	var idStrs []string
+	for idRows.Next() {
+		var idStr string
+		if err := idRows.Scan(&idStr); err != nil {
+			idRows.Close()
+			return nil, nil, err
+		}
+		idStrs = append(idStrs, idStr)
+	}
+	idRows.Close()
+	if err := idRows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	roles := make(Roles[K], len(idStrs))
+	parents := make(map[K]map[K]struct{}, len(idStrs))
+	for _, idStr := range idStrs {
+		id, err := s.keyParse(idStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		role, err := s.LoadRole(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		roles[id] = role
+
+		parentRows, err := s.db.Query(`SELECT parent_id FROM role_parent WHERE role_id = ?`, idStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		set := make(map[K]struct{})
+		for parentRows.Next() {
+			var parentStr string
+			if err := parentRows.Scan(&parentStr); err != nil {
+				parentRows.Close()
+				return nil, nil, err
+			}
+			parentID, err := s.keyParse(parentStr)
+			if err != nil {
+				parentRows.Close()
+				return nil, nil, err
+			}
+			set[parentID] = empty
+		}
+		parentRows.Close()
+		if err := parentRows.Err(); err != nil {
+			return nil, nil, err
+		}
+		if len(set) > 0 {
+			parents[id] = set
+		}
+	}
+	return roles, parents, nil
+}
+
+// ReplaceAll discards every row in roles, role_permission and
+// role_parent and repopulates them from `roles`/`parents`, all inside a
+// single transaction, so a mid-way failure rolls back to the
+// pre-existing graph instead of leaving a mix of old and new rows.
+func (s *SQLStore[K]) ReplaceAll(roles Roles[K], parents map[K][]K) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range []string{
+		`DELETE FROM role_permission`,
+		`DELETE FROM role_parent`,
+		`DELETE FROM roles`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, r := range roles {
+		id := s.keyString(r.ID)
+		if _, err := tx.Exec(`INSERT INTO roles (id) VALUES (?)`, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, p := range r.Permissions {
+			permID := s.keyString(p.ID)
+			actions, err := json.Marshal(p.Actions())
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			if _, err := tx.Exec(
+				`INSERT INTO role_permission (role_id, permission_id, description, actions) VALUES (?, ?, ?, ?)`,
+				id, permID, p.Description, string(actions),
+			); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	for id, ps := range parents {
+		idStr := s.keyString(id)
+		for _, parent := range ps {
+			if _, err := tx.Exec(`INSERT INTO role_parent (role_id, parent_id) VALUES (?, ?)`, idStr, s.keyString(parent)); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
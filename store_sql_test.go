@@ -0,0 +1,61 @@
+package gorbac
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestSQLStore(t *testing.T) *SQLStore[string] {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := NewSQLStore[string](db, func(s string) string { return s }, func(s string) (string, error) { return s, nil })
+	if err := store.Schema(); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// TestSQLStoreScopesPermissionActionsPerRole proves that two roles
+// holding the same permission ID with different action sets don't
+// clobber each other's stored actions: saving editor after viewer must
+// not leak editor's Delete into viewer's reloaded permissions.
+func TestSQLStoreScopesPermissionActionsPerRole(t *testing.T) {
+	store := newTestSQLStore(t)
+
+	viewer := NewRole("viewer")
+	viewer.Permissions["users"] = NewPermission("users", "users", Read)
+	if err := store.SaveRole(viewer); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := NewRole("editor")
+	editor.Permissions["users"] = NewPermission("users", "users", Read, Update, Delete)
+	if err := store.SaveRole(editor); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, _, err := store.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roles["viewer"].Permissions["users"].Permit(Delete) {
+		t.Error("expected viewer's users permission to stay Read-only after saving editor with the same permission ID")
+	}
+	if !roles["editor"].Permissions["users"].Permit(Delete) {
+		t.Error("expected editor to keep its Delete action")
+	}
+
+	reloaded, err := store.LoadRole("viewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Permissions["users"].Permit(Update) || reloaded.Permissions["users"].Permit(Delete) {
+		t.Error("expected LoadRole(viewer) to not pick up editor's actions")
+	}
+}
@@ -0,0 +1,152 @@
+package gorbac
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// failingStore wraps a MemoryStore and fails every SaveParents call,
+// to verify that RBAC only mutates its in-memory parent graph after a
+// successful store write.
+type failingStore[K comparable] struct {
+	*MemoryStore[K]
+}
+
+func (s *failingStore[K]) SaveParents(id K, parents []K) error {
+	return errors.New("simulated store failure")
+}
+
+// failingReplaceAllStore wraps a MemoryStore and fails every ReplaceAll
+// call, to verify that a bad JSON import doesn't partially persist the
+// new graph through the store.
+type failingReplaceAllStore[K comparable] struct {
+	*MemoryStore[K]
+}
+
+func (s *failingReplaceAllStore[K]) ReplaceAll(roles Roles[K], parents map[K][]K) error {
+	return errors.New("simulated store failure")
+}
+
+func TestSetParentDoesNotMutateMemoryOnStoreFailure(t *testing.T) {
+	store := &failingStore[string]{MemoryStore: NewMemoryStore[string]()}
+	rbac, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.Add(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.Add(NewRole("manager")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rbac.SetParent("manager", "admin"); err == nil {
+		t.Fatal("expected SetParent to fail when the store fails")
+	}
+	if parents, _ := rbac.GetParents("manager"); len(parents) != 0 {
+		t.Errorf("expected no parents in memory after a failed store write, got %v", parents)
+	}
+}
+
+func TestSetParentsDoesNotMutateMemoryOnStoreFailure(t *testing.T) {
+	store := &failingStore[string]{MemoryStore: NewMemoryStore[string]()}
+	rbac, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.Add(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.Add(NewRole("manager")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rbac.SetParents("manager", []string{"admin"}); err == nil {
+		t.Fatal("expected SetParents to fail when the store fails")
+	}
+	if parents, _ := rbac.GetParents("manager"); len(parents) != 0 {
+		t.Errorf("expected no parents in memory after a failed store write, got %v", parents)
+	}
+}
+
+func TestMemoryStoreDeleteRoleScrubsDanglingParents(t *testing.T) {
+	store := NewMemoryStore[string]()
+	if err := store.SaveRole(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRole(NewRole("manager")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveParents("manager", []string{"admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteRole("admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, parents, err := store.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parents["manager"]["admin"]; ok {
+		t.Error("expected the deleted role to be scrubbed from manager's parents")
+	}
+}
+
+func TestJSONStoreDeleteRoleScrubsDanglingParents(t *testing.T) {
+	store := NewJSONStore[string](filepath.Join(t.TempDir(), "rbac.json"))
+	if err := store.SaveRole(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRole(NewRole("manager")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveParents("manager", []string{"admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteRole("admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, parents, err := store.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parents["manager"]["admin"]; ok {
+		t.Error("expected the deleted role to be scrubbed from manager's parents")
+	}
+}
+
+func TestUnmarshalJSONDoesNotPartiallyPersistOnStoreFailure(t *testing.T) {
+	backing := NewMemoryStore[string]()
+	if err := backing.SaveRole(NewRole("admin")); err != nil {
+		t.Fatal(err)
+	}
+	store := &failingReplaceAllStore[string]{MemoryStore: backing}
+	rbac, err := NewWithStore[string](store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := []byte(`{"roles":[{"id":"staff","permissions":[]}],"inheritance":[]}`)
+	if err := rbac.UnmarshalJSON(doc); err == nil {
+		t.Fatal("expected UnmarshalJSON to fail when the store's ReplaceAll fails")
+	}
+
+	if _, _, err := rbac.Get("staff"); err != ErrRoleNotExist {
+		t.Errorf("expected the in-memory graph to stay untouched, got err=%v", err)
+	}
+	roles, _, err := store.LoadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := roles["staff"]; ok {
+		t.Error("expected the store to stay untouched, but it picked up the failed import's role")
+	}
+	if _, ok := roles["admin"]; !ok {
+		t.Error("expected the store's pre-existing role to survive the failed import")
+	}
+}